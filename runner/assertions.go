@@ -0,0 +1,230 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jhump/protoreflect/dynamic"
+)
+
+// AssertionOp is a comparison operator usable in an AssertionRule.
+type AssertionOp string
+
+// Supported AssertionOp values.
+const (
+	AssertEq       AssertionOp = "eq"
+	AssertNe       AssertionOp = "ne"
+	AssertContains AssertionOp = "contains"
+	AssertRegex    AssertionOp = "regex"
+	AssertGt       AssertionOp = "gt"
+	AssertLt       AssertionOp = "lt"
+)
+
+// AssertionRule checks a single JSONPath-style field of a response message
+// against an expected value. MinCount/MaxCount instead bound how many
+// messages a server-streaming RPC delivers; a rule sets either Path/Op/
+// Expected or MinCount/MaxCount, not both.
+type AssertionRule struct {
+	// Path is a JSONPath-style expression into the response, marshaled to
+	// JSON, e.g. "$.status" or "$.items[0].id".
+	Path string
+
+	// Op is the comparison operator applied to the value found at Path.
+	Op AssertionOp
+
+	// Expected is the value Op compares the field against.
+	Expected interface{}
+
+	// MinCount/MaxCount bound the number of messages a server-streaming RPC
+	// may deliver. Zero means unbounded. Evaluated once per stream.
+	MinCount int
+	MaxCount int
+}
+
+// AssertionFailureError is returned by a Worker when a response fails one or
+// more assertion rules, so the report can tally it as a distinct error class
+// from transport-level RPC errors.
+type AssertionFailureError struct {
+	Failures []error
+}
+
+func (e *AssertionFailureError) Error() string {
+	if len(e.Failures) == 0 {
+		return "assertion failed"
+	}
+
+	msgs := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		msgs[i] = f.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+type assertionFieldError struct {
+	rule  AssertionRule
+	value interface{}
+}
+
+func (e *assertionFieldError) Error() string {
+	return fmt.Sprintf("assertion failed: path %q op %q expected %v got %v",
+		e.rule.Path, e.rule.Op, e.rule.Expected, e.value)
+}
+
+// evaluateAssertions marshals msg to JSON and evaluates each non-count rule's
+// Path/Op against it, returning one error per failing rule.
+func evaluateAssertions(rules []AssertionRule, msg *dynamic.Message) []error {
+	if len(rules) == 0 || msg == nil {
+		return nil
+	}
+
+	raw, err := msg.MarshalJSON()
+	if err != nil {
+		return []error{fmt.Errorf("marshaling response for assertions: %w", err)}
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return []error{fmt.Errorf("unmarshaling response for assertions: %w", err)}
+	}
+
+	return evaluateJSONDocAssertions(rules, doc)
+}
+
+// evaluateJSONDocAssertions evaluates each non-count rule's Path/Op against
+// an already-decoded JSON document (the output of json.Unmarshal into
+// interface{}). Split out from evaluateAssertions so the lookup/operator
+// logic is testable without a *dynamic.Message.
+func evaluateJSONDocAssertions(rules []AssertionRule, doc interface{}) []error {
+	var failures []error
+	for _, rule := range rules {
+		if rule.MinCount > 0 || rule.MaxCount > 0 {
+			continue // stream-level rules are checked via checkStreamCounts
+		}
+
+		value, ok := lookupJSONPath(doc, rule.Path)
+		if !ok || !assertionHolds(rule.Op, value, rule.Expected) {
+			failures = append(failures, &assertionFieldError{rule: rule, value: value})
+		}
+	}
+
+	return failures
+}
+
+// checkStreamCounts evaluates the MinCount/MaxCount rules against the total
+// number of messages a server-streaming RPC delivered.
+func checkStreamCounts(rules []AssertionRule, count int) []error {
+	var failures []error
+	for _, rule := range rules {
+		if rule.MinCount == 0 && rule.MaxCount == 0 {
+			continue
+		}
+		if rule.MinCount > 0 && count < rule.MinCount {
+			failures = append(failures, fmt.Errorf("stream delivered %d message(s), want at least %d", count, rule.MinCount))
+		}
+		if rule.MaxCount > 0 && count > rule.MaxCount {
+			failures = append(failures, fmt.Errorf("stream delivered %d message(s), want at most %d", count, rule.MaxCount))
+		}
+	}
+	return failures
+}
+
+var jsonPathIndex = regexp.MustCompile(`^([^\[\]]*)\[(\d+)\]$`)
+
+// lookupJSONPath resolves a small JSONPath subset ("$.a.b[2].c") against a
+// decoded JSON document (the output of json.Unmarshal into interface{}).
+func lookupJSONPath(doc interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return doc, true
+	}
+
+	cur := doc
+	for _, segment := range strings.Split(path, ".") {
+		field, idx, hasIdx := splitJSONPathIndex(segment)
+
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		next, ok := m[field]
+		if !ok {
+			return nil, false
+		}
+
+		if hasIdx {
+			arr, ok := next.([]interface{})
+			if !ok || idx < 0 || idx >= len(arr) {
+				return nil, false
+			}
+			cur = arr[idx]
+		} else {
+			cur = next
+		}
+	}
+
+	return cur, true
+}
+
+// splitJSONPathIndex splits a path segment like "items[0]" into its field
+// name and array index.
+func splitJSONPathIndex(segment string) (field string, idx int, hasIdx bool) {
+	m := jsonPathIndex.FindStringSubmatch(segment)
+	if m == nil {
+		return segment, 0, false
+	}
+
+	idx, err := strconv.Atoi(m[2])
+	if err != nil {
+		return segment, 0, false
+	}
+
+	return m[1], idx, true
+}
+
+func assertionHolds(op AssertionOp, actual, expected interface{}) bool {
+	switch op {
+	case AssertEq:
+		return fmt.Sprint(actual) == fmt.Sprint(expected)
+	case AssertNe:
+		return fmt.Sprint(actual) != fmt.Sprint(expected)
+	case AssertContains:
+		return strings.Contains(fmt.Sprint(actual), fmt.Sprint(expected))
+	case AssertRegex:
+		re, err := regexp.Compile(fmt.Sprint(expected))
+		if err != nil {
+			return false
+		}
+		return re.MatchString(fmt.Sprint(actual))
+	case AssertGt, AssertLt:
+		a, aok := toFloat(actual)
+		e, eok := toFloat(expected)
+		if !aok || !eok {
+			return false
+		}
+		if op == AssertGt {
+			return a > e
+		}
+		return a < e
+	default:
+		return false
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func mustDecode(t *testing.T, raw string) interface{} {
+	t.Helper()
+	var doc interface{}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatalf("json.Unmarshal(%q): %v", raw, err)
+	}
+	return doc
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	doc := mustDecode(t, `{"status":"ok","count":3,"items":[{"id":1},{"id":2}]}`)
+
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantOk  bool
+		comment string
+	}{
+		{"$.status", "ok", true, "top-level field with $ prefix"},
+		{"status", "ok", true, "top-level field without $ prefix"},
+		{"$.count", float64(3), true, "numeric field"},
+		{"$.items[0].id", float64(1), true, "indexed array element field"},
+		{"$.items[1].id", float64(2), true, "second indexed array element"},
+		{"$.missing", nil, false, "missing field"},
+		{"$.items[5].id", nil, false, "out of range index"},
+		{"$", doc, true, "root path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			got, ok := lookupJSONPath(doc, tt.path)
+			if ok != tt.wantOk {
+				t.Fatalf("lookupJSONPath(%q) ok = %v, want %v", tt.path, ok, tt.wantOk)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("lookupJSONPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertionHolds(t *testing.T) {
+	tests := []struct {
+		op       AssertionOp
+		actual   interface{}
+		expected interface{}
+		want     bool
+	}{
+		{AssertEq, "ok", "ok", true},
+		{AssertEq, "ok", "fail", false},
+		{AssertNe, "ok", "fail", true},
+		{AssertNe, "ok", "ok", false},
+		{AssertContains, "hello world", "world", true},
+		{AssertContains, "hello world", "bye", false},
+		{AssertRegex, "v1.2.3", `^v\d+\.\d+\.\d+$`, true},
+		{AssertRegex, "nope", `^v\d+\.\d+\.\d+$`, false},
+		{AssertGt, float64(5), float64(3), true},
+		{AssertGt, float64(2), float64(3), false},
+		{AssertLt, float64(2), float64(3), true},
+		{AssertLt, float64(5), float64(3), false},
+	}
+
+	for _, tt := range tests {
+		if got := assertionHolds(tt.op, tt.actual, tt.expected); got != tt.want {
+			t.Errorf("assertionHolds(%s, %v, %v) = %v, want %v", tt.op, tt.actual, tt.expected, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateAssertions(t *testing.T) {
+	rules := []AssertionRule{
+		{Path: "$.status", Op: AssertEq, Expected: "ok"},
+		{Path: "$.count", Op: AssertGt, Expected: float64(10)},
+	}
+
+	doc := mustDecode(t, `{"status":"ok","count":3}`)
+	failures := evaluateJSONDocAssertions(rules, doc)
+	if len(failures) != 1 {
+		t.Fatalf("evaluateJSONDocAssertions() returned %d failures, want 1: %v", len(failures), failures)
+	}
+}
+
+func TestCheckStreamCounts(t *testing.T) {
+	rules := []AssertionRule{{MinCount: 2, MaxCount: 4}}
+
+	if failures := checkStreamCounts(rules, 3); len(failures) != 0 {
+		t.Errorf("checkStreamCounts(3) = %v, want no failures", failures)
+	}
+	if failures := checkStreamCounts(rules, 1); len(failures) != 1 {
+		t.Errorf("checkStreamCounts(1) = %v, want 1 failure (below MinCount)", failures)
+	}
+	if failures := checkStreamCounts(rules, 5); len(failures) != 1 {
+		t.Errorf("checkStreamCounts(5) = %v, want 1 failure (above MaxCount)", failures)
+	}
+}
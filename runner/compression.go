@@ -0,0 +1,164 @@
+package runner
+
+import (
+	"compress/flate"
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // registers "gzip"
+)
+
+// compressorNames lists the compressor names ghz knows how to register out
+// of the box, for use in flag/config validation and usage strings. Any other
+// name registered with the grpc encoding package (via a custom build or
+// plugin) is also accepted by RunConfig.Compressor.
+var compressorNames = []string{"gzip", "snappy", "zstd", "deflate"}
+
+// isKnownCompressor reports whether name is one of the compressors ghz
+// registers out of the box. Names outside this set are still passed through
+// to grpc.UseCompressor as-is, since any name registered with the grpc
+// encoding package (e.g. by a custom build) is valid too.
+func isKnownCompressor(name string) bool {
+	for _, n := range compressorNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	encoding.RegisterCompressor(snappyCompressor{})
+	encoding.RegisterCompressor(newDeflateCompressor())
+	encoding.RegisterCompressor(newZstdCompressor())
+}
+
+// snappyCompressor implements encoding.Compressor for "snappy", built
+// directly on top of github.com/golang/snappy's streaming framing format.
+type snappyCompressor struct{}
+
+func (snappyCompressor) Name() string { return "snappy" }
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+// deflateCompressor implements encoding.Compressor for "deflate" using the
+// standard library's compress/flate. *flate.Writer values are pooled since
+// they're relatively expensive to allocate.
+type deflateCompressor struct {
+	writers sync.Pool
+}
+
+func newDeflateCompressor() *deflateCompressor {
+	return &deflateCompressor{}
+}
+
+func (c *deflateCompressor) Name() string { return "deflate" }
+
+func (c *deflateCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if fw, ok := c.writers.Get().(*flate.Writer); ok {
+		fw.Reset(w)
+		return &pooledFlateWriter{Writer: fw, pool: &c.writers}, nil
+	}
+
+	fw, err := flate.NewWriter(w, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledFlateWriter{Writer: fw, pool: &c.writers}, nil
+}
+
+func (c *deflateCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return flate.NewReader(r), nil
+}
+
+// pooledFlateWriter returns its *flate.Writer to the owning pool on Close,
+// after flushing it the way flate.Writer.Close normally would.
+type pooledFlateWriter struct {
+	*flate.Writer
+	pool *sync.Pool
+}
+
+func (w *pooledFlateWriter) Close() error {
+	err := w.Writer.Close()
+	w.pool.Put(w.Writer)
+	return err
+}
+
+// zstdCompressor implements encoding.Compressor for "zstd" using
+// github.com/klauspost/compress/zstd. Encoders/decoders are pooled and reset
+// per call rather than shared, since neither type is safe for concurrent use
+// by multiple callers at once.
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+func newZstdCompressor() *zstdCompressor {
+	return &zstdCompressor{}
+}
+
+func (c *zstdCompressor) Name() string { return "zstd" }
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if enc, ok := c.encoders.Get().(*zstd.Encoder); ok {
+		enc.Reset(w)
+		return &pooledZstdWriter{Encoder: enc, pool: &c.encoders}, nil
+	}
+
+	enc, err := zstd.NewWriter(w)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledZstdWriter{Encoder: enc, pool: &c.encoders}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	if dec, ok := c.decoders.Get().(*zstd.Decoder); ok {
+		if err := dec.Reset(r); err != nil {
+			return nil, err
+		}
+		return &pooledZstdReader{Decoder: dec, pool: &c.decoders}, nil
+	}
+
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledZstdReader{Decoder: dec, pool: &c.decoders}, nil
+}
+
+// pooledZstdWriter returns its *zstd.Encoder to the owning pool on Close.
+type pooledZstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (w *pooledZstdWriter) Close() error {
+	err := w.Encoder.Close()
+	w.pool.Put(w.Encoder)
+	return err
+}
+
+// pooledZstdReader returns its *zstd.Decoder to the owning pool once the
+// underlying stream is fully consumed.
+type pooledZstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+}
+
+func (r *pooledZstdReader) Read(p []byte) (int, error) {
+	n, err := r.Decoder.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.Decoder)
+	}
+	return n, err
+}
@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/encoding"
+)
+
+func TestIsKnownCompressor(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"gzip", true},
+		{"snappy", true},
+		{"zstd", true},
+		{"deflate", true},
+		{"bzip2", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isKnownCompressor(tt.name); got != tt.want {
+			t.Errorf("isKnownCompressor(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompressorsRoundTrip(t *testing.T) {
+	want := []byte("the quick brown fox jumps over the lazy dog, repeated for good measure, " +
+		"the quick brown fox jumps over the lazy dog")
+
+	for _, name := range []string{"snappy", "deflate", "zstd"} {
+		t.Run(name, func(t *testing.T) {
+			c := encoding.GetCompressor(name)
+			if c == nil {
+				t.Fatalf("encoding.GetCompressor(%q) returned nil; not registered", name)
+			}
+
+			var buf bytes.Buffer
+			wc, err := c.Compress(&buf)
+			if err != nil {
+				t.Fatalf("Compress: %v", err)
+			}
+			if _, err := wc.Write(want); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if err := wc.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			r, err := c.Decompress(&buf)
+			if err != nil {
+				t.Fatalf("Decompress: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Fatalf("round trip = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCompressorsReusableAfterRoundTrip(t *testing.T) {
+	// Exercise the pooled Compress/Decompress path a second time to catch
+	// Reset bugs that only show up on reuse.
+	for _, name := range []string{"deflate", "zstd"} {
+		t.Run(name, func(t *testing.T) {
+			c := encoding.GetCompressor(name)
+			if c == nil {
+				t.Fatalf("encoding.GetCompressor(%q) returned nil; not registered", name)
+			}
+
+			for i := 0; i < 2; i++ {
+				var buf bytes.Buffer
+				wc, err := c.Compress(&buf)
+				if err != nil {
+					t.Fatalf("iteration %d: Compress: %v", i, err)
+				}
+				if _, err := wc.Write([]byte("payload")); err != nil {
+					t.Fatalf("iteration %d: Write: %v", i, err)
+				}
+				if err := wc.Close(); err != nil {
+					t.Fatalf("iteration %d: Close: %v", i, err)
+				}
+
+				r, err := c.Decompress(&buf)
+				if err != nil {
+					t.Fatalf("iteration %d: Decompress: %v", i, err)
+				}
+				got, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("iteration %d: ReadAll: %v", i, err)
+				}
+				if string(got) != "payload" {
+					t.Fatalf("iteration %d: round trip = %q, want %q", i, got, "payload")
+				}
+			}
+		})
+	}
+}
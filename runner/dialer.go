@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// dial establishes the runner's underlying grpc.ClientConn to target. base
+// carries whatever transport-security, authority, and user-agent dial
+// options the caller has already assembled; dial appends RunConfig's
+// keepalive/HTTP/2 transport tuning and chained client interceptors
+// (including one loaded from --interceptor-plugin, if configured) on top.
+func dial(ctx context.Context, target string, c *RunConfig, base ...grpc.DialOption) (*grpc.ClientConn, error) {
+	interceptorOpts, err := interceptorDialOptions(c)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := make([]grpc.DialOption, 0, len(base)+len(interceptorOpts))
+	opts = append(opts, base...)
+	opts = append(opts, transportDialOptions(c)...)
+	opts = append(opts, interceptorOpts...)
+
+	return grpc.DialContext(ctx, target, opts...)
+}
+
+// interceptorDialOptions builds the grpc.WithUnaryInterceptor/
+// WithStreamInterceptor dial options for RunConfig's configured interceptor
+// chain, appending one loaded from --interceptor-plugin when set.
+func interceptorDialOptions(c *RunConfig) ([]grpc.DialOption, error) {
+	unary := append([]grpc.UnaryClientInterceptor{}, c.unaryInterceptors...)
+	stream := append([]grpc.StreamClientInterceptor{}, c.streamInterceptors...)
+
+	if c.interceptorPluginPath != "" {
+		u, s, err := loadInterceptorPlugin(c.interceptorPluginPath)
+		if err != nil {
+			return nil, err
+		}
+		if u != nil {
+			unary = append(unary, u)
+		}
+		if s != nil {
+			stream = append(stream, s)
+		}
+	}
+
+	var opts []grpc.DialOption
+
+	if chain := chainUnaryInterceptors(unary); chain != nil {
+		opts = append(opts, grpc.WithUnaryInterceptor(chain))
+	}
+
+	if chain := chainStreamInterceptors(stream); chain != nil {
+		opts = append(opts, grpc.WithStreamInterceptor(chain))
+	}
+
+	return opts, nil
+}
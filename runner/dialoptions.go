@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"math"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// grpc-go's documented defaults for keepalive.ClientParameters, used to fill
+// in whichever of Time/Timeout RunConfig leaves unset when it sets any
+// keepalive field. Passing the
+// zero value for either one through literally would mean "ping every
+// instant, with a 0s timeout" rather than "use the default interval".
+const (
+	defaultKeepaliveTime    = time.Duration(math.MaxInt64)
+	defaultKeepaliveTimeout = 20 * time.Second
+)
+
+// transportDialOptions translates the keepalive and HTTP/2 transport tuning
+// fields on RunConfig into the corresponding grpc.DialOption values. Zero
+// values for maxRecvMsgSize/maxSendMsgSize/initialWindowSize/
+// initialConnWindowSize are left untouched so callers fall back to grpc-go's
+// own defaults (e.g. the 4 MiB message size limit) instead of ghz silently
+// overriding them with zero.
+func transportDialOptions(c *RunConfig) []grpc.DialOption {
+	var opts []grpc.DialOption
+
+	if c.keepaliveTime > 0 || c.keepaliveTimeout > 0 || c.keepalivePermitWithoutStream {
+		kp := keepalive.ClientParameters{
+			Time:                defaultKeepaliveTime,
+			Timeout:             defaultKeepaliveTimeout,
+			PermitWithoutStream: c.keepalivePermitWithoutStream,
+		}
+		if c.keepaliveTime > 0 {
+			kp.Time = c.keepaliveTime
+		}
+		if c.keepaliveTimeout > 0 {
+			kp.Timeout = c.keepaliveTimeout
+		}
+
+		opts = append(opts, grpc.WithKeepaliveParams(kp))
+	}
+
+	if c.maxRecvMsgSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(c.maxRecvMsgSize)))
+	}
+
+	if c.maxSendMsgSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(c.maxSendMsgSize)))
+	}
+
+	if c.initialWindowSize > 0 {
+		opts = append(opts, grpc.WithInitialWindowSize(c.initialWindowSize))
+	}
+
+	if c.initialConnWindowSize > 0 {
+		opts = append(opts, grpc.WithInitialConnWindowSize(c.initialConnWindowSize))
+	}
+
+	return opts
+}
@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func recordingUnaryInterceptor(name string, order *[]string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		*order = append(*order, name+":before")
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		*order = append(*order, name+":after")
+		return err
+	}
+}
+
+func TestChainUnaryInterceptorsOrder(t *testing.T) {
+	var order []string
+
+	chain := chainUnaryInterceptors([]grpc.UnaryClientInterceptor{
+		recordingUnaryInterceptor("a", &order),
+		recordingUnaryInterceptor("b", &order),
+		recordingUnaryInterceptor("c", &order),
+	})
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		order = append(order, "invoker")
+		return nil
+	}
+
+	if err := chain(context.Background(), "/svc/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("chain returned error: %v", err)
+	}
+
+	want := []string{"a:before", "b:before", "c:before", "invoker", "c:after", "b:after", "a:after"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestChainUnaryInterceptorsEmptyAndSingle(t *testing.T) {
+	if chain := chainUnaryInterceptors(nil); chain != nil {
+		t.Error("chainUnaryInterceptors(nil) should return nil")
+	}
+
+	var order []string
+	single := recordingUnaryInterceptor("only", &order)
+	if chain := chainUnaryInterceptors([]grpc.UnaryClientInterceptor{single}); chain == nil {
+		t.Error("chainUnaryInterceptors with one interceptor should not return nil")
+	}
+}
+
+func recordingStreamInterceptor(name string, order *[]string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		*order = append(*order, name+":before")
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		*order = append(*order, name+":after")
+		return s, err
+	}
+}
+
+func TestChainStreamInterceptorsOrder(t *testing.T) {
+	var order []string
+
+	chain := chainStreamInterceptors([]grpc.StreamClientInterceptor{
+		recordingStreamInterceptor("a", &order),
+		recordingStreamInterceptor("b", &order),
+	})
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		order = append(order, "streamer")
+		return nil, nil
+	}
+
+	if _, err := chain(context.Background(), &grpc.StreamDesc{}, nil, "/svc/Method", streamer); err != nil {
+		t.Fatalf("chain returned error: %v", err)
+	}
+
+	want := []string{"a:before", "b:before", "streamer", "b:after", "a:after"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestChainStreamInterceptorsEmpty(t *testing.T) {
+	if chain := chainStreamInterceptors(nil); chain != nil {
+		t.Error("chainStreamInterceptors(nil) should return nil")
+	}
+}
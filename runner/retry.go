@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryResult summarizes a logical request's retry behavior for the report:
+// how many attempts it took in total, how many of those were retries, and
+// the final gRPC status code it finished with.
+type RetryResult struct {
+	Attempts int
+	Retries  int
+	Status   codes.Code
+}
+
+// RetryResultFunc receives a RetryResult once a logical request, including
+// all of its retries, has finished, so the report can tally retry counts
+// and final status per request.
+type RetryResultFunc func(RetryResult)
+
+// RetryConfig controls per-call retry behavior for a Worker. A nil
+// RetryConfig (the default) disables retries: every reqNum is attempted
+// exactly once, matching ghz's original behavior.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of times a single logical request is
+	// attempted, including the first try. A value <= 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the computed delay between retries.
+	MaxBackoff time.Duration
+
+	// BackoffMultiplier scales the previous backoff on each subsequent
+	// retry. Defaults to 1.6 when zero.
+	BackoffMultiplier float64
+
+	// Jitter is the fraction (0-1) of random variance applied to each
+	// computed backoff. Defaults to 0.2 when zero; a negative value disables
+	// jitter.
+	Jitter float64
+
+	// RetryableStatusCodes lists the gRPC status codes eligible for retry.
+	// Any other code, including OK, stops the retry loop.
+	RetryableStatusCodes []codes.Code
+}
+
+func (rc *RetryConfig) maxAttempts() int {
+	if rc == nil || rc.MaxAttempts <= 1 {
+		return 1
+	}
+	return rc.MaxAttempts
+}
+
+func (rc *RetryConfig) backoffMultiplier() float64 {
+	if rc == nil || rc.BackoffMultiplier == 0 {
+		return 1.6
+	}
+	return rc.BackoffMultiplier
+}
+
+func (rc *RetryConfig) jitter() float64 {
+	if rc == nil || rc.Jitter == 0 {
+		return 0.2
+	}
+	return rc.Jitter
+}
+
+// backoffFor returns the jittered delay to wait before attempt n+1, where n
+// is the 1-indexed attempt that just failed (the delay before the 2nd
+// attempt is backoffFor(1)).
+func (rc *RetryConfig) backoffFor(n int) time.Duration {
+	if rc == nil || rc.InitialBackoff <= 0 {
+		return 0
+	}
+
+	d := float64(rc.InitialBackoff) * math.Pow(rc.backoffMultiplier(), float64(n-1))
+	if rc.MaxBackoff > 0 && d > float64(rc.MaxBackoff) {
+		d = float64(rc.MaxBackoff)
+	}
+
+	if j := rc.jitter(); j > 0 {
+		d *= 1 + (rand.Float64()*2-1)*j
+	}
+
+	return time.Duration(d)
+}
+
+// isRetryable reports whether err's gRPC status code is in the configured
+// retryable set.
+func (rc *RetryConfig) isRetryable(err error) bool {
+	if rc == nil || err == nil {
+		return false
+	}
+
+	code := status.Code(err)
+	for _, c := range rc.RetryableStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
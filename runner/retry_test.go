@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryConfigMaxAttempts(t *testing.T) {
+	tests := []struct {
+		name string
+		rc   *RetryConfig
+		want int
+	}{
+		{"nil config disables retries", nil, 1},
+		{"zero MaxAttempts disables retries", &RetryConfig{}, 1},
+		{"MaxAttempts of 1 disables retries", &RetryConfig{MaxAttempts: 1}, 1},
+		{"MaxAttempts above 1 is honored", &RetryConfig{MaxAttempts: 5}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rc.maxAttempts(); got != tt.want {
+				t.Errorf("maxAttempts() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryConfigBackoffFor(t *testing.T) {
+	rc := &RetryConfig{
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        1 * time.Second,
+		BackoffMultiplier: 2,
+		Jitter:            -1, // disable jitter for deterministic assertions (0 would select the 0.2 default)
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, 1 * time.Second}, // capped by MaxBackoff
+	}
+
+	for _, tt := range tests {
+		if got := rc.backoffFor(tt.attempt); got != tt.want {
+			t.Errorf("backoffFor(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryConfigBackoffForNoInitialBackoff(t *testing.T) {
+	var rc *RetryConfig
+	if got := rc.backoffFor(1); got != 0 {
+		t.Errorf("backoffFor(1) on nil config = %v, want 0", got)
+	}
+
+	rc = &RetryConfig{}
+	if got := rc.backoffFor(1); got != 0 {
+		t.Errorf("backoffFor(1) with InitialBackoff 0 = %v, want 0", got)
+	}
+}
+
+func TestRetryConfigBackoffForJitterBounds(t *testing.T) {
+	rc := &RetryConfig{
+		InitialBackoff: 100 * time.Millisecond,
+		Jitter:         0.2,
+	}
+
+	for i := 0; i < 50; i++ {
+		d := rc.backoffFor(1)
+		if d < 80*time.Millisecond || d > 120*time.Millisecond {
+			t.Fatalf("backoffFor(1) = %v, want within 100ms +/- 20%%", d)
+		}
+	}
+}
+
+func TestRetryConfigIsRetryable(t *testing.T) {
+	rc := &RetryConfig{RetryableStatusCodes: []codes.Code{codes.Unavailable, codes.DeadlineExceeded}}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error is never retryable", nil, false},
+		{"retryable code", status.Error(codes.Unavailable, "down"), true},
+		{"another retryable code", status.Error(codes.DeadlineExceeded, "timeout"), true},
+		{"non-retryable code", status.Error(codes.InvalidArgument, "bad"), false},
+		{"non-status error treated as Unknown", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rc.isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	var nilRC *RetryConfig
+	if nilRC.isRetryable(status.Error(codes.Unavailable, "down")) {
+		t.Error("nil RetryConfig should never consider an error retryable")
+	}
+}
@@ -15,8 +15,8 @@ import (
 	"go.uber.org/multierr"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // ConditionChecker tells worker whether to run
@@ -73,7 +73,14 @@ func (w *Worker) runWorker(cond ConditionChecker, stopOnCond bool) error {
 
 				rErr := w.makeRequest(reqNum)
 
-				err = multierr.Append(err, rErr)
+				// Keep only the most recent failure here: onRetryResult/the
+				// stats handler already report every request's outcome, so
+				// accumulating one error per request for the worker's whole
+				// lifetime (often the full duration of a load test) would
+				// otherwise grow without bound.
+				if rErr != nil {
+					err = rErr
+				}
 			} else if stopOnCond {
 				return err
 			}
@@ -139,8 +146,13 @@ func (w *Worker) makeRequest(reqNum int64) error {
 		reqMD = &metadata.MD{}
 	}
 
-	if w.config.enableCompression {
-		reqMD.Append("grpc-accept-encoding", gzip.Name)
+	if w.config.compressorName != "" {
+		reqMD.Append("grpc-accept-encoding", w.config.compressorName)
+
+		if w.config.hasLog && !isKnownCompressor(w.config.compressorName) {
+			w.config.log.Debugw("Using compressor not registered by ghz itself; relying on a custom build to have registered it",
+				"workerID", w.workerID, "compressor", w.config.compressorName)
+		}
 	}
 
 	ctx := context.Background()
@@ -181,18 +193,54 @@ func (w *Worker) makeRequest(reqNum int64) error {
 	inputIdx := int((reqNum - 1) % int64(inputsLen)) // we want to start from inputs[0] so dec reqNum
 	unaryInput := inputs[inputIdx]
 
-	// RPC errors are handled via stats handler
-	if w.mtd.IsClientStreaming() && w.mtd.IsServerStreaming() {
-		_ = w.makeBidiRequest(&ctx, inputs)
-	} else if w.mtd.IsClientStreaming() {
-		_ = w.makeClientStreamingRequest(&ctx, inputs)
-	} else if w.mtd.IsServerStreaming() {
-		_ = w.makeServerStreamingRequest(&ctx, unaryInput)
-	} else {
-		_ = w.makeUnaryRequest(&ctx, reqMD, unaryInput)
+	// RPC errors are handled via stats handler. Each attempt below is a
+	// distinct RPC on the same stub, so retries show up independently in
+	// the stats handler / histogram.
+	retry := w.config.retry
+	maxAttempts := retry.maxAttempts()
+
+	var rErr error
+	attempts := 0
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var received bool
+		attempts = attempt
+
+		if w.mtd.IsClientStreaming() && w.mtd.IsServerStreaming() {
+			received, rErr = w.makeBidiRequest(&ctx, inputs)
+		} else if w.mtd.IsClientStreaming() {
+			received, rErr = w.makeClientStreamingRequest(&ctx, inputs)
+		} else if w.mtd.IsServerStreaming() {
+			received, rErr = w.makeServerStreamingRequest(&ctx, unaryInput)
+		} else {
+			// unary RPCs never partially succeed, so they're always safe to retry
+			rErr = w.makeUnaryRequest(&ctx, reqMD, unaryInput)
+		}
+
+		if attempt == maxAttempts || received || !retry.isRetryable(rErr) {
+			break
+		}
+
+		if w.config.hasLog {
+			w.config.log.Debugw("Retrying request", "workerID", w.workerID,
+				"call", w.mtd.GetFullyQualifiedName(), "attempt", attempt, "error", rErr)
+		}
+
+		time.Sleep(retry.backoffFor(attempt))
+	}
+
+	result := RetryResult{Attempts: attempts, Retries: attempts - 1, Status: status.Code(rErr)}
+
+	if w.config.hasLog {
+		w.config.log.Infow("Request complete", "workerID", w.workerID,
+			"call", w.mtd.GetFullyQualifiedName(), "attempts", result.Attempts,
+			"retries", result.Retries, "status", result.Status)
+	}
+
+	if w.config.onRetryResult != nil {
+		w.config.onRetryResult(result)
 	}
 
-	return err
+	return multierr.Append(err, rErr)
 }
 
 func (w *Worker) getMessages(ctd *callTemplateData, inputData []byte) ([]*dynamic.Message, error) {
@@ -225,12 +273,21 @@ func (w *Worker) getMessages(ctd *callTemplateData, inputData []byte) ([]*dynami
 	return inputs, nil
 }
 
-func (w *Worker) makeClientStreamingRequest(ctx *context.Context, input []*dynamic.Message) error {
+// makeClientStreamingRequest sends input on a client-streaming RPC and
+// returns whether a final response was ever received from the server,
+// alongside the resulting error.
+//
+// The received flag returned here, and by makeServerStreamingRequest and
+// makeBidiRequest below, lets makeRequest apply gRPC's transparent-retry
+// rule: only retry a streaming call if no message was ever received, since
+// a stream that has already delivered data cannot be safely retried.
+func (w *Worker) makeClientStreamingRequest(ctx *context.Context, input []*dynamic.Message) (bool, error) {
 	var str *grpcdynamic.ClientStream
 	var err error
+	var received bool
 	var callOptions = []grpc.CallOption{}
-	if w.config.enableCompression {
-		callOptions = append(callOptions, grpc.UseCompressor(gzip.Name))
+	if w.config.compressorName != "" {
+		callOptions = append(callOptions, grpc.UseCompressor(w.config.compressorName))
 	}
 	str, err = w.stub.InvokeRpcClientStream(*ctx, w.mtd, callOptions...)
 
@@ -245,24 +302,28 @@ func (w *Worker) makeClientStreamingRequest(ctx *context.Context, input []*dynam
 	for err == nil {
 		inputLen := len(input)
 		if input == nil || inputLen == 0 {
-			res, closeErr := str.CloseAndReceive()
+			var res proto.Message
+			res, err = str.CloseAndReceive()
+			received = err == nil
 
 			if w.config.hasLog {
 				w.config.log.Debugw("Close and receive", "workerID", w.workerID, "call type", "client-streaming",
 					"call", w.mtd.GetFullyQualifiedName(),
-					"response", res, "error", closeErr)
+					"response", res, "error", err)
 			}
 
 			break
 		}
 
 		if counter == inputLen {
-			res, closeErr := str.CloseAndReceive()
+			var res proto.Message
+			res, err = str.CloseAndReceive()
+			received = err == nil
 
 			if w.config.hasLog {
 				w.config.log.Debugw("Close and receive", "workerID", w.workerID, "call type", "client-streaming",
 					"call", w.mtd.GetFullyQualifiedName(),
-					"response", res, "error", closeErr)
+					"response", res, "error", err)
 			}
 
 			break
@@ -287,25 +348,30 @@ func (w *Worker) makeClientStreamingRequest(ctx *context.Context, input []*dynam
 		if err == io.EOF {
 			// We get EOF on send if the server says "go away"
 			// We have to use CloseAndReceive to get the actual code
-			res, closeErr := str.CloseAndReceive()
+			var res proto.Message
+			res, err = str.CloseAndReceive()
+			received = err == nil
 
 			if w.config.hasLog {
 				w.config.log.Debugw("Close and receive", "workerID", w.workerID, "call type", "client-streaming",
 					"call", w.mtd.GetFullyQualifiedName(),
-					"response", res, "error", closeErr)
+					"response", res, "error", err)
 			}
 
 			break
 		}
 		counter++
 	}
-	return nil
+	return received, err
 }
 
-func (w *Worker) makeServerStreamingRequest(ctx *context.Context, input *dynamic.Message) error {
+// makeServerStreamingRequest receives messages on a server-streaming RPC and
+// returns whether any message was ever received, alongside the resulting
+// error. See the received flag note on makeClientStreamingRequest above.
+func (w *Worker) makeServerStreamingRequest(ctx *context.Context, input *dynamic.Message) (bool, error) {
 	var callOptions = []grpc.CallOption{}
-	if w.config.enableCompression {
-		callOptions = append(callOptions, grpc.UseCompressor(gzip.Name))
+	if w.config.compressorName != "" {
+		callOptions = append(callOptions, grpc.UseCompressor(w.config.compressorName))
 	}
 	str, err := w.stub.InvokeRpcServerStream(*ctx, w.mtd, input, callOptions...)
 
@@ -316,8 +382,13 @@ func (w *Worker) makeServerStreamingRequest(ctx *context.Context, input *dynamic
 			"input", input, "error", err)
 	}
 
+	var received bool
+	var count int
+	var failures []error
+
 	for err == nil {
-		res, err := str.RecvMsg()
+		var res *dynamic.Message
+		res, err = str.RecvMsg()
 
 		if w.config.hasLog {
 			w.config.log.Debugw("Receive message", "workerID", w.workerID, "call type", "server-streaming",
@@ -331,17 +402,36 @@ func (w *Worker) makeServerStreamingRequest(ctx *context.Context, input *dynamic
 			}
 			break
 		}
+
+		received = true
+		count++
+
+		if len(w.config.assertions) > 0 {
+			failures = append(failures, evaluateAssertions(w.config.assertions, res)...)
+		}
 	}
 
-	return err
+	if err == nil && len(w.config.assertions) > 0 {
+		failures = append(failures, checkStreamCounts(w.config.assertions, count)...)
+	}
+
+	if err == nil && len(failures) > 0 {
+		if w.config.hasLog {
+			w.config.log.Debugw("Assertion failed", "workerID", w.workerID, "call type", "server-streaming",
+				"call", w.mtd.GetFullyQualifiedName(), "errors", failures)
+		}
+		err = &AssertionFailureError{Failures: failures}
+	}
+
+	return received, err
 }
 
 func (w *Worker) makeUnaryRequest(ctx *context.Context, reqMD *metadata.MD, input *dynamic.Message) error {
 	var res proto.Message
 	var resErr error
 	var callOptions = []grpc.CallOption{}
-	if w.config.enableCompression {
-		callOptions = append(callOptions, grpc.UseCompressor(gzip.Name))
+	if w.config.compressorName != "" {
+		callOptions = append(callOptions, grpc.UseCompressor(w.config.compressorName))
 	}
 
 	res, resErr = w.stub.InvokeRpc(*ctx, w.mtd, input, callOptions...)
@@ -353,15 +443,32 @@ func (w *Worker) makeUnaryRequest(ctx *context.Context, reqMD *metadata.MD, inpu
 			"response", res, "error", resErr)
 	}
 
+	if resErr == nil && len(w.config.assertions) > 0 {
+		if dm, ok := res.(*dynamic.Message); ok {
+			if failures := evaluateAssertions(w.config.assertions, dm); len(failures) > 0 {
+				if w.config.hasLog {
+					w.config.log.Debugw("Assertion failed", "workerID", w.workerID, "call type", "unary",
+						"call", w.mtd.GetFullyQualifiedName(), "errors", failures)
+				}
+				return &AssertionFailureError{Failures: failures}
+			}
+		}
+	}
+
 	return resErr
 }
 
-func (w *Worker) makeBidiRequest(ctx *context.Context, input []*dynamic.Message) error {
+// makeBidiRequest exchanges messages on a bidi-streaming RPC and returns
+// whether any message was ever received from the server, alongside the
+// error from opening the stream. Errors from sending or receiving on an
+// open stream are not returned. See the received flag note on
+// makeClientStreamingRequest above.
+func (w *Worker) makeBidiRequest(ctx *context.Context, input []*dynamic.Message) (bool, error) {
 	var str *grpcdynamic.BidiStream
 	var err error
 	var callOptions = []grpc.CallOption{}
-	if w.config.enableCompression {
-		callOptions = append(callOptions, grpc.UseCompressor(gzip.Name))
+	if w.config.compressorName != "" {
+		callOptions = append(callOptions, grpc.UseCompressor(w.config.compressorName))
 	}
 	str, err = w.stub.InvokeRpcBidiStream(*ctx, w.mtd, callOptions...)
 
@@ -372,7 +479,7 @@ func (w *Worker) makeBidiRequest(ctx *context.Context, input []*dynamic.Message)
 				"call", w.mtd.GetFullyQualifiedName(), "error", err)
 		}
 
-		return err
+		return false, err
 	}
 
 	counter := 0
@@ -389,9 +496,11 @@ func (w *Worker) makeBidiRequest(ctx *context.Context, input []*dynamic.Message)
 				"call", w.mtd.GetFullyQualifiedName(), "error", closeErr)
 		}
 
-		return nil
+		return false, nil
 	}
 
+	var received int32
+
 	go func() {
 		for {
 			res, err := str.RecvMsg()
@@ -406,6 +515,8 @@ func (w *Worker) makeBidiRequest(ctx *context.Context, input []*dynamic.Message)
 				close(recvDone)
 				break
 			}
+
+			atomic.StoreInt32(&received, 1)
 		}
 	}()
 
@@ -443,5 +554,5 @@ func (w *Worker) makeBidiRequest(ctx *context.Context, input []*dynamic.Message)
 		<-recvDone
 	}
 
-	return nil
+	return atomic.LoadInt32(&received) == 1, nil
 }